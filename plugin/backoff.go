@@ -0,0 +1,34 @@
+package plugin
+
+import "time"
+
+// backoff implements the doubling backoff shared by the plugin's retry and
+// reconnect loops, mirroring the discipline Nomad's own device instance
+// manager uses for stats collection (baseline doubling up to a limit).
+type backoff struct {
+	baseline time.Duration
+	limit    time.Duration
+	current  time.Duration
+}
+
+func newBackoff(baseline, limit time.Duration) *backoff {
+	return &backoff{baseline: baseline, limit: limit}
+}
+
+// Duration returns the next backoff duration and advances the sequence.
+func (b *backoff) Duration() time.Duration {
+	if b.current == 0 {
+		b.current = b.baseline
+	} else if b.current < b.limit {
+		b.current *= 2
+		if b.current > b.limit {
+			b.current = b.limit
+		}
+	}
+	return b.current
+}
+
+// Reset returns the backoff to its initial state, e.g. after a success.
+func (b *backoff) Reset() {
+	b.current = 0
+}
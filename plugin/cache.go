@@ -0,0 +1,263 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/plugins/device"
+)
+
+// CacheConfig configures the on-disk cache used to keep serving fingerprints
+// and reservations while every upstream is unreachable. Leaving path empty
+// keeps the cache in memory only, so it still smooths over a transient
+// outage but does not survive a plugin restart.
+type CacheConfig struct {
+	Path       string `codec:"path"`
+	StaleAfter string `codec:"stale_after"`
+}
+
+// cachedReservation is a previously granted reservation, kept so Reserve can
+// answer from cache during an outage and so it can be replayed to the
+// backend once reachable again.
+type cachedReservation struct {
+	DeviceIDs   []string                     `json:"device_ids"`
+	Reservation *device.ContainerReservation `json:"reservation"`
+	GrantedAt   time.Time                    `json:"granted_at"`
+	Replayed    bool                         `json:"replayed"`
+}
+
+// cacheState is the JSON document persisted to disk.
+type cacheState struct {
+	LastFingerprint   *device.FingerprintResponse   `json:"last_fingerprint"`
+	LastFingerprintAt time.Time                     `json:"last_fingerprint_at"`
+	Reservations      map[string]*cachedReservation `json:"reservations"`
+}
+
+// cache persists the last known-good fingerprint and every granted
+// reservation, so an unreachable backend degrades to stale-but-present
+// devices instead of Nomad simply forgetting about them.
+type cache struct {
+	path       string
+	staleAfter time.Duration
+	logger     log.Logger
+
+	mu    sync.Mutex
+	state cacheState
+}
+
+func newCache(cfg *CacheConfig, logger log.Logger) (*cache, error) {
+	staleAfter := 5 * time.Minute
+	path := ""
+	if cfg != nil {
+		path = cfg.Path
+		if cfg.StaleAfter != "" {
+			parsed, err := time.ParseDuration(cfg.StaleAfter)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stale_after %q: %v", cfg.StaleAfter, err)
+			}
+			staleAfter = parsed
+		}
+	}
+	c := &cache{
+		path:       path,
+		staleAfter: staleAfter,
+		logger:     logger,
+		state: cacheState{
+			Reservations: make(map[string]*cachedReservation),
+		},
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load reads the persisted cache from disk, if a path is configured and the
+// file exists. A missing file just means there's nothing cached yet.
+func (c *cache) load() error {
+	if c.path == "" {
+		return nil
+	}
+	contents, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cache file %q: %v", c.path, err)
+	}
+	var state cacheState
+	if err := json.Unmarshal(contents, &state); err != nil {
+		return fmt.Errorf("failed to parse cache file %q: %v", c.path, err)
+	}
+	if state.Reservations == nil {
+		state.Reservations = make(map[string]*cachedReservation)
+	}
+	c.mu.Lock()
+	c.state = state
+	c.mu.Unlock()
+	return nil
+}
+
+// save writes the cache to disk, if a path is configured, via a temp file
+// and rename so a crash mid-write can't leave a truncated cache behind.
+func (c *cache) save() error {
+	if c.path == "" {
+		return nil
+	}
+	c.mu.Lock()
+	marshalled, err := json.Marshal(c.state)
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %v", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	if err := os.WriteFile(tmp, marshalled, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache file %q: %v", tmp, err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to replace cache file %q: %v", c.path, err)
+	}
+	return nil
+}
+
+// recordFingerprint remembers the last successful fingerprint response, so
+// it can be served (marked stale after a while) once the backend goes away.
+func (c *cache) recordFingerprint(resp *device.FingerprintResponse) {
+	c.mu.Lock()
+	c.state.LastFingerprint = resp
+	c.state.LastFingerprintAt = time.Now()
+	c.mu.Unlock()
+	if err := c.save(); err != nil {
+		c.logFailure("save", err)
+	}
+}
+
+// fingerprint returns the last cached fingerprint, marking every device
+// Unhealthy once it's older than staleAfter. It returns false if nothing has
+// ever been cached.
+func (c *cache) fingerprint() (*device.FingerprintResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state.LastFingerprint == nil {
+		return nil, false
+	}
+	if time.Since(c.state.LastFingerprintAt) < c.staleAfter {
+		return c.state.LastFingerprint, true
+	}
+	stale := &device.FingerprintResponse{
+		Devices: make([]*device.Device, len(c.state.LastFingerprint.Devices)),
+	}
+	for i, d := range c.state.LastFingerprint.Devices {
+		copyDev := *d
+		copyDev.Healthy = false
+		copyDev.HealthDescription = "stale, backend unreachable"
+		stale.Devices[i] = &copyDev
+	}
+	return stale, true
+}
+
+// reservationKey turns a set of device IDs into a stable cache key,
+// independent of the order Nomad happened to request them in.
+func reservationKey(deviceIDs []string) string {
+	sorted := append([]string(nil), deviceIDs...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// recordReservation remembers a reservation the backend just granted, so it
+// can be replayed or served from cache later.
+func (c *cache) recordReservation(deviceIDs []string, res *device.ContainerReservation) {
+	key := reservationKey(deviceIDs)
+	c.mu.Lock()
+	c.state.Reservations[key] = &cachedReservation{
+		DeviceIDs:   deviceIDs,
+		Reservation: res,
+		GrantedAt:   time.Now(),
+		Replayed:    true,
+	}
+	c.mu.Unlock()
+	if err := c.save(); err != nil {
+		c.logFailure("save", err)
+	}
+}
+
+// reservation returns a previously granted reservation for this exact set of
+// device IDs, so Reserve can answer from cache when the backend is down.
+func (c *cache) reservation(deviceIDs []string) (*device.ContainerReservation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.state.Reservations[reservationKey(deviceIDs)]
+	if !ok {
+		return nil, false
+	}
+	return entry.Reservation, true
+}
+
+// recordUnreplayedReservation marks a reservation as served from cache
+// rather than confirmed by the current backend, so it gets replayed the next
+// time an upstream is reachable.
+func (c *cache) recordUnreplayedReservation(deviceIDs []string, res *device.ContainerReservation) {
+	key := reservationKey(deviceIDs)
+	c.mu.Lock()
+	c.state.Reservations[key] = &cachedReservation{
+		DeviceIDs:   deviceIDs,
+		Reservation: res,
+		GrantedAt:   time.Now(),
+		Replayed:    false,
+	}
+	c.mu.Unlock()
+	if err := c.save(); err != nil {
+		c.logFailure("save", err)
+	}
+}
+
+// pendingReplays returns every cached reservation that was served from cache
+// but never confirmed against the backend.
+func (c *cache) pendingReplays() []*cachedReservation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var pending []*cachedReservation
+	for _, entry := range c.state.Reservations {
+		if !entry.Replayed {
+			pending = append(pending, entry)
+		}
+	}
+	return pending
+}
+
+// markReplayed records that a cached reservation was successfully replayed
+// to the backend, preferring the backend's view of the reservation over
+// whatever was cached.
+func (c *cache) markReplayed(deviceIDs []string, confirmed *device.ContainerReservation) {
+	key := reservationKey(deviceIDs)
+	c.mu.Lock()
+	c.state.Reservations[key] = &cachedReservation{
+		DeviceIDs:   deviceIDs,
+		Reservation: confirmed,
+		GrantedAt:   time.Now(),
+		Replayed:    true,
+	}
+	c.mu.Unlock()
+	if err := c.save(); err != nil {
+		c.logFailure("save", err)
+	}
+}
+
+// logFailure reports a persistence error without failing the request that
+// triggered it: the cache is a best-effort optimization, so a write failure
+// should degrade to in-memory-only behavior rather than surface to Nomad.
+func (c *cache) logFailure(op string, err error) {
+	if c.logger != nil {
+		c.logger.Warn("cache "+op+" failed", "error", err.Error())
+	}
+}
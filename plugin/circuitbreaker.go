@@ -0,0 +1,47 @@
+package plugin
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// circuitBreakerTripThreshold is the number of consecutive failures on
+	// a single endpoint that opens its breaker.
+	circuitBreakerTripThreshold = 5
+	// circuitBreakerCooldown is how long an opened breaker short-circuits
+	// further calls before allowing another attempt through.
+	circuitBreakerCooldown = 30 * time.Second
+)
+
+// circuitBreaker short-circuits calls to a single (upstream, endpoint) pair
+// once it has seen enough consecutive failures, instead of letting every
+// caller retry a backend that is known to be down.
+type circuitBreaker struct {
+	mu          sync.Mutex
+	consecutive int
+	openUntil   time.Time
+}
+
+// allow reports whether a call should be attempted.
+func (c *circuitBreaker) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Now().After(c.openUntil)
+}
+
+func (c *circuitBreaker) recordSuccess() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.consecutive = 0
+	c.openUntil = time.Time{}
+}
+
+func (c *circuitBreaker) recordFailure() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.consecutive++
+	if c.consecutive >= circuitBreakerTripThreshold {
+		c.openUntil = time.Now().Add(circuitBreakerCooldown)
+	}
+}
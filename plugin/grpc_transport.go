@@ -0,0 +1,188 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/plugins/device"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	proxydevicepb "github.com/yyogo/nomad-proxy-device-plugin/proto"
+)
+
+const (
+	grpcReconnectBaseline = time.Second
+	grpcReconnectLimit    = time.Minute
+)
+
+// grpcTransport talks to a backend implementing the RemoteDeviceProvider
+// gRPC service, which streams fingerprint and stats updates instead of
+// being polled. The stream is kept open and reconnected with exponential
+// backoff whenever it breaks.
+type grpcTransport struct {
+	logger     log.Logger
+	address    string
+	tlsConfig  *TLSConfig
+	dialOption grpc.DialOption
+}
+
+var _ Transport = &grpcTransport{}
+
+func newGRPCTransport(config Config, logger log.Logger) (*grpcTransport, error) {
+	if len(config.Upstreams) != 1 {
+		return nil, fmt.Errorf("the grpc transport requires exactly one upstream, got %d", len(config.Upstreams))
+	}
+	tlsConfig, err := buildTLSConfig(config.TLS)
+	if err != nil {
+		return nil, err
+	}
+	dialOption := grpc.WithTransportCredentials(insecure.NewCredentials())
+	if tlsConfig != nil {
+		dialOption = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+	return &grpcTransport{
+		logger:     logger,
+		address:    config.Upstreams[0].Address,
+		tlsConfig:  config.TLS,
+		dialOption: dialOption,
+	}, nil
+}
+
+func (t *grpcTransport) dial(ctx context.Context) (*grpc.ClientConn, proxydevicepb.RemoteDeviceProviderClient, error) {
+	conn, err := grpc.DialContext(ctx, t.address, t.dialOption, grpc.WithBlock())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to dial %q: %v", t.address, err)
+	}
+	return conn, proxydevicepb.NewRemoteDeviceProviderClient(conn), nil
+}
+
+func (t *grpcTransport) RunFingerprint(ctx context.Context, ch chan<- *device.FingerprintResponse) {
+	retry := newBackoff(grpcReconnectBaseline, grpcReconnectLimit)
+	for {
+		if t.runFingerprintStream(ctx, ch, retry) {
+			return
+		}
+		select {
+		case <-time.After(retry.Duration()):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runFingerprintStream dials once and forwards updates until the stream
+// breaks or ctx is canceled. It returns true if the caller should stop
+// retrying (ctx canceled). retry is reset after every successfully received
+// update so a long-lived stream doesn't carry a stale backoff into its next
+// reconnect.
+func (t *grpcTransport) runFingerprintStream(ctx context.Context, ch chan<- *device.FingerprintResponse, retry *backoff) bool {
+	conn, client, err := t.dial(ctx)
+	if err != nil {
+		t.logger.Error("failed to connect", "error", err.Error())
+		return false
+	}
+	defer conn.Close()
+
+	stream, err := client.Fingerprint(ctx, &proxydevicepb.FingerprintRequest{})
+	if err != nil {
+		t.logger.Error("failed to open fingerprint stream", "error", err.Error())
+		return false
+	}
+	for {
+		update, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return true
+			}
+			t.logger.Error("fingerprint stream broke", "error", err.Error())
+			return false
+		}
+		retry.Reset()
+		var response device.FingerprintResponse
+		if err := json.Unmarshal(update.Json, &response); err != nil {
+			t.logger.Error("failed to unmarshal fingerprint update", "error", err.Error())
+			continue
+		}
+		select {
+		case ch <- &response:
+		case <-ctx.Done():
+			return true
+		}
+	}
+}
+
+func (t *grpcTransport) RunStats(ctx context.Context, interval time.Duration, ch chan<- *device.StatsResponse) {
+	retry := newBackoff(grpcReconnectBaseline, grpcReconnectLimit)
+	for {
+		if t.runStatsStream(ctx, interval, ch, retry) {
+			return
+		}
+		select {
+		case <-time.After(retry.Duration()):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *grpcTransport) runStatsStream(ctx context.Context, interval time.Duration, ch chan<- *device.StatsResponse, retry *backoff) bool {
+	conn, client, err := t.dial(ctx)
+	if err != nil {
+		t.logger.Error("failed to connect", "error", err.Error())
+		return false
+	}
+	defer conn.Close()
+
+	stream, err := client.Stats(ctx, &proxydevicepb.StatsRequest{IntervalMs: interval.Milliseconds()})
+	if err != nil {
+		t.logger.Error("failed to open stats stream", "error", err.Error())
+		return false
+	}
+	for {
+		update, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return true
+			}
+			t.logger.Error("stats stream broke", "error", err.Error())
+			return false
+		}
+		retry.Reset()
+		var response device.StatsResponse
+		if err := json.Unmarshal(update.Json, &response); err != nil {
+			t.logger.Error("failed to unmarshal stats update", "error", err.Error())
+			continue
+		}
+		select {
+		case ch <- &response:
+		case <-ctx.Done():
+			return true
+		}
+	}
+}
+
+func (t *grpcTransport) Reserve(deviceIDs []string) (*device.ContainerReservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	conn, client, err := t.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	update, err := client.Reserve(ctx, &proxydevicepb.ReserveRequest{DeviceIds: deviceIDs})
+	if err != nil {
+		return nil, fmt.Errorf("reserve rpc failed: %v", err)
+	}
+	var reservation device.ContainerReservation
+	if err := json.Unmarshal(update.Json, &reservation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reservation: %v", err)
+	}
+	return &reservation, nil
+}
@@ -0,0 +1,675 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/plugins/device"
+)
+
+// httpTransport is the default Transport: it talks to one or more REST
+// backends for fingerprint and stats updates, in poll, long-poll or SSE
+// mode, and merges their responses into one. It also remembers which
+// upstream last advertised each device ID, so Reserve can be routed to the
+// right place.
+type httpTransport struct {
+	logger            log.Logger
+	upstreams         []*upstream
+	fingerprintPeriod time.Duration
+	fingerprintMode   string
+	statsMode         string
+	retry             *retryPolicy
+	client            *http.Client
+	auth              Authenticator
+	cache             *cache
+
+	mu          sync.Mutex
+	deviceOwner map[string]*upstream
+
+	replayCh chan struct{}
+}
+
+var _ Transport = &httpTransport{}
+
+func newHTTPTransport(config Config, logger log.Logger) (*httpTransport, error) {
+	if len(config.Upstreams) == 0 {
+		return nil, fmt.Errorf("at least one upstream must be configured")
+	}
+	period, err := time.ParseDuration(config.FingerprintPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period %q: %v", config.FingerprintPeriod, err)
+	}
+	retry, err := newRetryPolicy(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFingerprintMode(config.FingerprintMode); err != nil {
+		return nil, err
+	}
+	if err := validateFingerprintMode(config.StatsMode); err != nil {
+		return nil, err
+	}
+	auth, err := newAuthenticator(config.Auth)
+	if err != nil {
+		return nil, err
+	}
+	client, err := buildHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := newCache(config.Cache, logger)
+	if err != nil {
+		return nil, err
+	}
+	upstreams := make([]*upstream, len(config.Upstreams))
+	for i, cfg := range config.Upstreams {
+		u, err := newUpstream(cfg)
+		if err != nil {
+			return nil, err
+		}
+		upstreams[i] = u
+	}
+	return &httpTransport{
+		logger:            logger,
+		upstreams:         upstreams,
+		fingerprintPeriod: period,
+		fingerprintMode:   config.FingerprintMode,
+		statsMode:         config.StatsMode,
+		retry:             retry,
+		client:            client,
+		auth:              auth,
+		cache:             cache,
+		deviceOwner:       make(map[string]*upstream),
+		replayCh:          make(chan struct{}, 1),
+	}, nil
+}
+
+func (t *httpTransport) RunFingerprint(ctx context.Context, ch chan<- *device.FingerprintResponse) {
+	t.startReplayLoop(ctx)
+	if t.fingerprintMode == fingerprintModeSSE {
+		t.runFingerprintSSE(ctx, ch)
+		return
+	}
+	for {
+		start := time.Now()
+		select {
+		case ch <- t.fanoutFingerprint(ctx):
+		case <-ctx.Done():
+			return
+		}
+		// In long_poll mode the upstream request itself normally blocks for
+		// close to fingerprintPeriod, so there's usually nothing left to wait
+		// for. But when every upstream is in its failure cooldown, or a
+		// misbehaving backend answers before wait elapses, the round returns
+		// almost instantly; enforce the same floor between iteration starts
+		// so that case doesn't spin tight for up to upstreamBackoffLimit.
+		wait := t.fingerprintPeriod
+		if t.fingerprintMode == fingerprintModeLongPoll {
+			wait -= time.Since(start)
+		}
+		if wait <= 0 {
+			continue
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// fanoutFingerprint queries every upstream concurrently and merges the
+// resulting device lists into a single response, updating the device ->
+// upstream ownership map used by Reserve. If every upstream is unreachable,
+// it falls back to the last cached fingerprint instead of reporting no
+// devices at all.
+func (t *httpTransport) fanoutFingerprint(ctx context.Context) *device.FingerprintResponse {
+	perUpstream := make([][]*device.Device, len(t.upstreams))
+	succeeded := make([]bool, len(t.upstreams))
+	var wg sync.WaitGroup
+	for i, u := range t.upstreams {
+		i, u := i, u
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			perUpstream[i], succeeded[i] = t.fingerprintUpstream(ctx, u)
+		}()
+	}
+	wg.Wait()
+
+	groups := make([]upstreamDevices, len(t.upstreams))
+	anySuccess := false
+	for i, u := range t.upstreams {
+		groups[i] = upstreamDevices{u: u, devices: perUpstream[i]}
+		anySuccess = anySuccess || succeeded[i]
+	}
+	merged, owners := t.mergeDeviceGroups(groups)
+	t.mu.Lock()
+	t.deviceOwner = owners
+	t.mu.Unlock()
+	return t.cacheFingerprint(ctx, merged, anySuccess)
+}
+
+// upstreamDevices pairs an upstream with its most recently seen devices, for
+// mergeDeviceGroups.
+type upstreamDevices struct {
+	u       *upstream
+	devices []*device.Device
+}
+
+// mergeDeviceGroups combines each upstream's devices into a single response
+// and builds the device -> upstream ownership map used by Reserve. With more
+// than one upstream configured, device IDs are namespaced with their
+// upstream's name so that two backends advertising the same ID don't merge
+// into a single Nomad-visible device owned by whichever upstream happened to
+// be merged last; backendDeviceID reverses the namespacing before a
+// reservation is sent back to the owning backend. A collision that survives
+// namespacing (e.g. two upstreams sharing a name) is logged and the later
+// device is dropped rather than silently reassigning ownership.
+func (t *httpTransport) mergeDeviceGroups(groups []upstreamDevices) (*device.FingerprintResponse, map[string]*upstream) {
+	merged := &device.FingerprintResponse{}
+	owners := make(map[string]*upstream)
+	namespaced := len(t.upstreams) > 1
+	for _, g := range groups {
+		for _, d := range g.devices {
+			id := d.ID
+			if namespaced {
+				id = g.u.name + "/" + d.ID
+			}
+			if _, collision := owners[id]; collision {
+				t.logger.Warn("dropping device with colliding namespaced ID", "upstream", g.u.name, "id", id)
+				continue
+			}
+			copyDev := *d
+			copyDev.ID = id
+			merged.Devices = append(merged.Devices, &copyDev)
+			owners[id] = g.u
+		}
+	}
+	return merged, owners
+}
+
+// backendDeviceID reverses the namespacing mergeDeviceGroups applies to
+// device IDs when more than one upstream is configured, so Reserve sends the
+// backend its own unprefixed ID rather than the one Nomad knows the device
+// by.
+func (t *httpTransport) backendDeviceID(u *upstream, id string) string {
+	if len(t.upstreams) <= 1 {
+		return id
+	}
+	return strings.TrimPrefix(id, u.name+"/")
+}
+
+// cacheFingerprint records a successful fingerprint for later outages, or,
+// if no upstream actually succeeded this round (merged can still be
+// non-empty, since a failed upstream reports its last known devices as
+// unhealthy rather than nothing), serves the last cached one instead. A
+// successful fingerprint also nudges the replay loop to re-submit any
+// reservations that were only ever confirmed from cache.
+func (t *httpTransport) cacheFingerprint(ctx context.Context, merged *device.FingerprintResponse, success bool) *device.FingerprintResponse {
+	if success {
+		t.cache.recordFingerprint(merged)
+		t.triggerReplay()
+		return merged
+	}
+	if cached, ok := t.cache.fingerprint(); ok {
+		return cached
+	}
+	return merged
+}
+
+// startReplayLoop starts a dedicated goroutine that replays cached
+// reservations whenever triggerReplay signals one is due, for the lifetime
+// of ctx. Running it off the fingerprint goroutine means a slow or large
+// replay batch can't delay fingerprint emission to Nomad. It's started fresh
+// on every RunFingerprint call rather than once per transport, since Nomad
+// may call Fingerprint again with a new context on the same plugin instance
+// after the previous stream ends.
+func (t *httpTransport) startReplayLoop(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-t.replayCh:
+				t.replayReservations(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// triggerReplay asks the replay loop to run another pass, without blocking
+// if a pass is already pending.
+func (t *httpTransport) triggerReplay() {
+	select {
+	case t.replayCh <- struct{}{}:
+	default:
+	}
+}
+
+// replayReservations re-submits every reservation that was granted from
+// cache rather than confirmed by the backend, now that at least one upstream
+// is reachable again. Discrepancies between the cached and replayed
+// reservation are logged, preferring the backend's view.
+func (t *httpTransport) replayReservations(ctx context.Context) {
+	for _, entry := range t.cache.pendingReplays() {
+		u, err := t.ownerFor(entry.DeviceIDs)
+		if err != nil {
+			// The owning upstream isn't known yet; try again next round.
+			continue
+		}
+		backendIDs := make([]string, len(entry.DeviceIDs))
+		for i, id := range entry.DeviceIDs {
+			backendIDs[i] = t.backendDeviceID(u, id)
+		}
+		reqCtx, cancel := context.WithTimeout(ctx, u.timeout)
+		var confirmed device.ContainerReservation
+		err = t.jsonRequest(reqCtx, u, http.MethodPost, reserveEndpoint, "", backendIDs, &confirmed, nil)
+		cancel()
+		if err != nil {
+			t.logger.Warn("failed to replay cached reservation", "devices", entry.DeviceIDs, "error", err.Error())
+			continue
+		}
+		t.logger.Info("replayed cached reservation to backend", "devices", entry.DeviceIDs)
+		t.cache.markReplayed(entry.DeviceIDs, &confirmed)
+	}
+}
+
+// fingerprintUpstream queries a single upstream and returns its devices
+// along with whether the call actually succeeded. On failure it still
+// returns the upstream's last known devices, marked unhealthy, so callers
+// merging across upstreams don't lose track of them during an outage.
+func (t *httpTransport) fingerprintUpstream(ctx context.Context, u *upstream) ([]*device.Device, bool) {
+	if !u.available() {
+		return u.unhealthyDevices(fmt.Sprintf("upstream %s is in backoff after repeated failures", u.name)), false
+	}
+	timeout := u.timeout
+	query := ""
+	if t.fingerprintMode == fingerprintModeLongPoll {
+		timeout += t.fingerprintPeriod
+		query = u.longPollQuery(t.fingerprintPeriod)
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var response device.FingerprintResponse
+	var index string
+	if err := t.jsonRequest(reqCtx, u, http.MethodGet, fingerprintEndpoint, query, nil, &response, &index); err != nil {
+		wait := u.recordFailure()
+		t.logger.Error("upstream fingerprint failed", "upstream", u.name, "error", err.Error(), "backoff", wait)
+		return u.unhealthyDevices(fmt.Sprintf("upstream %s unreachable: %v", u.name, err)), false
+	}
+	u.recordSuccess()
+	u.setLastDevices(response.Devices)
+	if t.fingerprintMode == fingerprintModeLongPoll {
+		u.setLastIndex(index)
+	}
+	return response.Devices, true
+}
+
+// runFingerprintSSE keeps one persistent event stream per upstream open,
+// instead of polling, reconnecting each with capped backoff on failure.
+// Every event re-merges that upstream's latest devices with the others'.
+func (t *httpTransport) runFingerprintSSE(ctx context.Context, ch chan<- *device.FingerprintResponse) {
+	var wg sync.WaitGroup
+	for _, u := range t.upstreams {
+		u := u
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t.runFingerprintSSEUpstream(ctx, u, ch)
+		}()
+	}
+	wg.Wait()
+}
+
+func (t *httpTransport) runFingerprintSSEUpstream(ctx context.Context, u *upstream, ch chan<- *device.FingerprintResponse) {
+	retry := newBackoff(sseReconnectBaseline, sseReconnectLimit)
+	for {
+		err := t.streamSSE(ctx, u, fingerprintStreamEndpoint, func(payload []byte) {
+			var response device.FingerprintResponse
+			if err := json.Unmarshal(payload, &response); err != nil {
+				t.logger.Error("failed to unmarshal sse fingerprint event", "upstream", u.name, "error", err.Error())
+				return
+			}
+			u.recordSuccess()
+			u.setLastDevices(response.Devices)
+			retry.Reset()
+			select {
+			case ch <- t.mergedFingerprint(ctx):
+			case <-ctx.Done():
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			wait := u.recordFailure()
+			t.logger.Error("sse fingerprint stream broke", "upstream", u.name, "error", err.Error(), "backoff", wait)
+		}
+		select {
+		case <-time.After(retry.Duration()):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// mergedFingerprint rebuilds a FingerprintResponse from every configured
+// upstream's last known devices, refreshing the device -> upstream ownership
+// map. It is only called right after an SSE event was received, so that
+// upstream's success is what gates caching/replay below. An upstream whose
+// stream is currently broken contributes its last known devices marked
+// Unhealthy instead of the raw (and by now stale) snapshot, the same way
+// fanoutFingerprint handles a downed upstream in poll mode, so a backend
+// that drops off doesn't keep being reported healthy forever just because
+// the other upstreams keep streaming events.
+func (t *httpTransport) mergedFingerprint(ctx context.Context) *device.FingerprintResponse {
+	groups := make([]upstreamDevices, len(t.upstreams))
+	for i, u := range t.upstreams {
+		devices := u.devices()
+		if !u.available() {
+			devices = u.unhealthyDevices(fmt.Sprintf("upstream %s is in backoff after repeated failures", u.name))
+		}
+		groups[i] = upstreamDevices{u: u, devices: devices}
+	}
+	merged, owners := t.mergeDeviceGroups(groups)
+	t.mu.Lock()
+	t.deviceOwner = owners
+	t.mu.Unlock()
+	return t.cacheFingerprint(ctx, merged, true)
+}
+
+func (t *httpTransport) RunStats(ctx context.Context, interval time.Duration, ch chan<- *device.StatsResponse) {
+	if t.statsMode == fingerprintModeSSE {
+		t.runStatsSSE(ctx, ch)
+		return
+	}
+	for {
+		start := time.Now()
+		select {
+		case ch <- t.fanoutStats(ctx, interval):
+		case <-ctx.Done():
+			return
+		}
+		// See the matching comment in RunFingerprint: a long_poll round that
+		// returns before interval elapses (every upstream in cooldown, or a
+		// misbehaving backend) still needs a floor sleep so it doesn't spin.
+		wait := interval
+		if t.statsMode == fingerprintModeLongPoll {
+			wait -= time.Since(start)
+		}
+		if wait <= 0 {
+			continue
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *httpTransport) fanoutStats(ctx context.Context, interval time.Duration) *device.StatsResponse {
+	perUpstream := make([][]*device.DeviceGroupStats, len(t.upstreams))
+	var wg sync.WaitGroup
+	for i, u := range t.upstreams {
+		i, u := i, u
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			perUpstream[i] = t.statsUpstream(ctx, u, interval)
+		}()
+	}
+	wg.Wait()
+
+	merged := &device.StatsResponse{}
+	for _, groups := range perUpstream {
+		merged.Groups = append(merged.Groups, groups...)
+	}
+	return merged
+}
+
+func (t *httpTransport) statsUpstream(ctx context.Context, u *upstream, interval time.Duration) []*device.DeviceGroupStats {
+	if !u.available() {
+		return nil
+	}
+	timeout := u.timeout
+	query := ""
+	if t.statsMode == fingerprintModeLongPoll {
+		timeout += interval
+		query = u.longPollQuery(interval)
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var response device.StatsResponse
+	var index string
+	if err := t.jsonRequest(reqCtx, u, http.MethodGet, statsEndpoint, query, nil, &response, &index); err != nil {
+		wait := u.recordFailure()
+		t.logger.Error("upstream stats failed", "upstream", u.name, "error", err.Error(), "backoff", wait)
+		return nil
+	}
+	u.recordSuccess()
+	if t.statsMode == fingerprintModeLongPoll {
+		u.setLastIndex(index)
+	}
+	return response.Groups
+}
+
+// runStatsSSE keeps one persistent event stream per upstream open instead
+// of polling, merging each update with the others' latest stats.
+func (t *httpTransport) runStatsSSE(ctx context.Context, ch chan<- *device.StatsResponse) {
+	var wg sync.WaitGroup
+	for _, u := range t.upstreams {
+		u := u
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t.runStatsSSEUpstream(ctx, u, ch)
+		}()
+	}
+	wg.Wait()
+}
+
+func (t *httpTransport) runStatsSSEUpstream(ctx context.Context, u *upstream, ch chan<- *device.StatsResponse) {
+	retry := newBackoff(sseReconnectBaseline, sseReconnectLimit)
+	for {
+		err := t.streamSSE(ctx, u, statsStreamEndpoint, func(payload []byte) {
+			var response device.StatsResponse
+			if err := json.Unmarshal(payload, &response); err != nil {
+				t.logger.Error("failed to unmarshal sse stats event", "upstream", u.name, "error", err.Error())
+				return
+			}
+			u.recordSuccess()
+			u.setLastStatsGroups(response.Groups)
+			retry.Reset()
+			select {
+			case ch <- t.mergedStats():
+			case <-ctx.Done():
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			wait := u.recordFailure()
+			t.logger.Error("sse stats stream broke", "upstream", u.name, "error", err.Error(), "backoff", wait)
+		}
+		select {
+		case <-time.After(retry.Duration()):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *httpTransport) mergedStats() *device.StatsResponse {
+	merged := &device.StatsResponse{}
+	for _, u := range t.upstreams {
+		merged.Groups = append(merged.Groups, u.statsGroups()...)
+	}
+	return merged
+}
+
+// Reserve asks the owning upstream to reserve deviceIDs. If the upstream
+// can't be determined or the request fails, it falls back to a previously
+// granted reservation for the same device IDs, if one is cached, so an
+// outage doesn't fail a placement Nomad already made once.
+func (t *httpTransport) Reserve(deviceIDs []string) (*device.ContainerReservation, error) {
+	u, err := t.ownerFor(deviceIDs)
+	if err != nil {
+		if cached, ok := t.cache.reservation(deviceIDs); ok {
+			t.logger.Warn("serving cached reservation, owning upstream unknown", "devices", deviceIDs, "error", err.Error())
+			t.cache.recordUnreplayedReservation(deviceIDs, cached)
+			return cached, nil
+		}
+		return nil, err
+	}
+	backendIDs := make([]string, len(deviceIDs))
+	for i, id := range deviceIDs {
+		backendIDs[i] = t.backendDeviceID(u, id)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), u.timeout)
+	defer cancel()
+	var unmarshalled device.ContainerReservation
+	if err := t.jsonRequest(ctx, u, http.MethodPost, reserveEndpoint, "", backendIDs, &unmarshalled, nil); err != nil {
+		if cached, ok := t.cache.reservation(deviceIDs); ok {
+			t.logger.Warn("serving cached reservation, upstream unreachable", "upstream", u.name, "devices", deviceIDs, "error", err.Error())
+			t.cache.recordUnreplayedReservation(deviceIDs, cached)
+			return cached, nil
+		}
+		return nil, err
+	}
+	t.cache.recordReservation(deviceIDs, &unmarshalled)
+	return &unmarshalled, nil
+}
+
+// ownerFor returns the upstream that advertised every device ID in the
+// request, as tracked from the last successful fingerprint.
+func (t *httpTransport) ownerFor(deviceIDs []string) (*upstream, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var owner *upstream
+	for _, id := range deviceIDs {
+		u, ok := t.deviceOwner[id]
+		if !ok {
+			return nil, fmt.Errorf("no known upstream advertised device %q", id)
+		}
+		if owner == nil {
+			owner = u
+		} else if owner != u {
+			return nil, fmt.Errorf("requested device IDs span multiple upstreams")
+		}
+	}
+	if owner == nil {
+		return nil, fmt.Errorf("no device IDs given")
+	}
+	return owner, nil
+}
+
+// consulIndexHeader is the response header a Consul/Nomad style blocking
+// query returns its monotonic index in; jsonRequest hands it back through its
+// index argument, and the caller records it with setLastIndex so the next
+// longPollQuery's index= reflects the backend's own cursor rather than
+// something the client made up.
+const consulIndexHeader = "X-Consul-Index"
+
+// jsonRequest issues a JSON request against u, retrying transient failures
+// and retryable status codes with jittered exponential backoff, and
+// short-circuiting through the endpoint's circuit breaker once it has
+// tripped. A non-retryable HTTP response is returned as *HTTPError without
+// being retried or counted against the breaker, since it means the backend
+// is up and simply rejected the call. If index is non-nil, it is set to the
+// backend's consulIndexHeader value on success.
+func (t *httpTransport) jsonRequest(ctx context.Context, u *upstream, method, endpoint, query string, input interface{}, output interface{}, index *string) error {
+	breaker := u.breakerFor(endpoint)
+	if !breaker.allow() {
+		return fmt.Errorf("circuit breaker open for upstream %s endpoint %s", u.name, endpoint)
+	}
+
+	var body []byte
+	if input != nil {
+		marshalled, err := json.Marshal(input)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %v", err)
+		}
+		body = marshalled
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= t.retry.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(t.retry.backoff(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err := t.doRequest(ctx, u, method, endpoint, query, body, input != nil, output, index)
+		if err == nil {
+			breaker.recordSuccess()
+			return nil
+		}
+		lastErr = err
+
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && !t.retry.retryableStatus(httpErr.StatusCode) {
+			return err
+		}
+		breaker.recordFailure()
+	}
+	return lastErr
+}
+
+func (t *httpTransport) doRequest(ctx context.Context, u *upstream, method, endpoint, query string, body []byte, hasBody bool, output interface{}, index *string) error {
+	var reqBody io.Reader
+	if hasBody {
+		reqBody = bytes.NewReader(body)
+	}
+	target := u.url(endpoint)
+	if query != "" {
+		target.RawQuery = query
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+	if hasBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := t.auth.Apply(req, body); err != nil {
+		return fmt.Errorf("failed to authenticate request: %v", err)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode >= 400 {
+		return &HTTPError{StatusCode: resp.StatusCode, Body: respBody}
+	}
+	if output != nil {
+		if err := json.Unmarshal(respBody, output); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %v", err)
+		}
+	}
+	if index != nil {
+		if got := resp.Header.Get(consulIndexHeader); got != "" {
+			*index = got
+		}
+	}
+	return nil
+}
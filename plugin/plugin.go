@@ -1,13 +1,7 @@
 package plugin
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
@@ -37,26 +31,101 @@ var (
 	}
 
 	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
-		"address": hclspec.NewDefault(
-			hclspec.NewAttr("address", "string", false),
-			hclspec.NewLiteral("\"127.0.0.1:5656\""),
-		),
+		"upstream": hclspec.NewBlockList("upstream", hclspec.NewObject(map[string]*hclspec.Spec{
+			"name":    hclspec.NewAttr("name", "string", false),
+			"address": hclspec.NewAttr("address", "string", true),
+			"timeout": hclspec.NewAttr("timeout", "string", false),
+		})),
 		"fingerprint_period": hclspec.NewDefault(
 			hclspec.NewAttr("fingerprint_period", "string", false),
 			hclspec.NewLiteral("\"1m\""),
 		),
+		"transport": hclspec.NewDefault(
+			hclspec.NewAttr("transport", "string", false),
+			hclspec.NewLiteral("\"http\""),
+		),
+		"tls": hclspec.NewBlock("tls", false, hclspec.NewObject(map[string]*hclspec.Spec{
+			"ca":          hclspec.NewAttr("ca", "string", false),
+			"cert":        hclspec.NewAttr("cert", "string", false),
+			"key":         hclspec.NewAttr("key", "string", false),
+			"server_name": hclspec.NewAttr("server_name", "string", false),
+		})),
+		"max_retries": hclspec.NewDefault(
+			hclspec.NewAttr("max_retries", "number", false),
+			hclspec.NewLiteral("3"),
+		),
+		"retry_base": hclspec.NewDefault(
+			hclspec.NewAttr("retry_base", "string", false),
+			hclspec.NewLiteral("\"500ms\""),
+		),
+		"retry_max": hclspec.NewDefault(
+			hclspec.NewAttr("retry_max", "string", false),
+			hclspec.NewLiteral("\"10s\""),
+		),
+		"retryable_status_codes": hclspec.NewDefault(
+			hclspec.NewAttr("retryable_status_codes", "list(number)", false),
+			hclspec.NewLiteral("[429, 502, 503, 504]"),
+		),
+		"fingerprint_mode": hclspec.NewDefault(
+			hclspec.NewAttr("fingerprint_mode", "string", false),
+			hclspec.NewLiteral("\"poll\""),
+		),
+		"stats_mode": hclspec.NewDefault(
+			hclspec.NewAttr("stats_mode", "string", false),
+			hclspec.NewLiteral("\"poll\""),
+		),
+		"auth": hclspec.NewBlock("auth", false, hclspec.NewObject(map[string]*hclspec.Spec{
+			"bearer": hclspec.NewBlock("bearer", false, hclspec.NewObject(map[string]*hclspec.Spec{
+				"token":      hclspec.NewAttr("token", "string", false),
+				"token_file": hclspec.NewAttr("token_file", "string", false),
+			})),
+			"basic": hclspec.NewBlock("basic", false, hclspec.NewObject(map[string]*hclspec.Spec{
+				"username": hclspec.NewAttr("username", "string", false),
+				"password": hclspec.NewAttr("password", "string", false),
+			})),
+			"mtls": hclspec.NewBlock("mtls", false, hclspec.NewObject(map[string]*hclspec.Spec{
+				"ca":          hclspec.NewAttr("ca", "string", false),
+				"cert":        hclspec.NewAttr("cert", "string", false),
+				"key":         hclspec.NewAttr("key", "string", false),
+				"server_name": hclspec.NewAttr("server_name", "string", false),
+			})),
+			"hmac": hclspec.NewBlock("hmac", false, hclspec.NewObject(map[string]*hclspec.Spec{
+				"secret": hclspec.NewAttr("secret", "string", false),
+			})),
+			"vault": hclspec.NewBlock("vault", false, hclspec.NewObject(map[string]*hclspec.Spec{
+				"agent_address": hclspec.NewAttr("agent_address", "string", false),
+				"kv_path":       hclspec.NewAttr("kv_path", "string", false),
+				"ttl":           hclspec.NewAttr("ttl", "string", false),
+			})),
+		})),
+		"cache": hclspec.NewBlock("cache", false, hclspec.NewObject(map[string]*hclspec.Spec{
+			"path": hclspec.NewAttr("path", "string", false),
+			"stale_after": hclspec.NewDefault(
+				hclspec.NewAttr("stale_after", "string", false),
+				hclspec.NewLiteral("\"5m\""),
+			),
+		})),
 	})
 )
 
 type Config struct {
-	Address           string `codec:"address"`
-	FingerprintPeriod string `codec:"fingerprint_period"`
+	Upstreams            []UpstreamConfig `codec:"upstream"`
+	FingerprintPeriod    string           `codec:"fingerprint_period"`
+	FingerprintMode      string           `codec:"fingerprint_mode"`
+	StatsMode            string           `codec:"stats_mode"`
+	Transport            string           `codec:"transport"`
+	TLS                  *TLSConfig       `codec:"tls"`
+	MaxRetries           int              `codec:"max_retries"`
+	RetryBase            string           `codec:"retry_base"`
+	RetryMax             string           `codec:"retry_max"`
+	RetryableStatusCodes []int            `codec:"retryable_status_codes"`
+	Auth                 *AuthConfig      `codec:"auth"`
+	Cache                *CacheConfig     `codec:"cache"`
 }
 
 type Plugin struct {
-	logger            log.Logger
-	address           *url.URL
-	fingerprintPeriod time.Duration
+	logger    log.Logger
+	transport Transport
 }
 
 var _ device.DevicePlugin = &Plugin{}
@@ -67,8 +136,7 @@ var _ device.DevicePlugin = &Plugin{}
 // a limit to the initialization that can be performed at this point.
 func NewPlugin(log log.Logger) *Plugin {
 	return &Plugin{
-		logger:            log.Named(pluginName),
-		fingerprintPeriod: DefaultInterval,
+		logger: log.Named(pluginName),
 	}
 }
 
@@ -96,16 +164,11 @@ func (d *Plugin) SetConfig(c *base.Config) error {
 		return err
 	}
 
-	address, err := url.Parse(config.Address)
+	transport, err := newTransport(config, d.logger)
 	if err != nil {
-		return fmt.Errorf("invalid address %q: %v", config.Address, err)
-	}
-	period, err := time.ParseDuration(config.FingerprintPeriod)
-	if err != nil {
-		return fmt.Errorf("invalid period %q: %v", config.FingerprintPeriod, err)
+		return err
 	}
-	d.address = address
-	d.fingerprintPeriod = period
+	d.transport = transport
 	d.logger.Info("config set", "config", log.Fmt("% #v", pretty.Formatter(config)))
 	return nil
 }
@@ -117,31 +180,13 @@ func (d *Plugin) Fingerprint(ctx context.Context) (<-chan *device.FingerprintRes
 	// Fingerprint returns a channel. The recommended way of organizing a plugin
 	// is to pass that into a long-running goroutine and return the channel immediately.
 	outCh := make(chan *device.FingerprintResponse)
-	go d.doFingerprint(ctx, outCh)
+	go func() {
+		defer close(outCh)
+		d.transport.RunFingerprint(ctx, outCh)
+	}()
 	return outCh, nil
 }
 
-func (d *Plugin) doFingerprint(ctx context.Context, ch chan<- *device.FingerprintResponse) {
-	defer close(ch)
-	for {
-		var response device.FingerprintResponse
-		if err := d.jsonRequest(ctx, http.MethodGet, fingerprintEndpoint, nil, &response); err == nil {
-			select {
-			case ch <- &response:
-			case <-ctx.Done():
-				return
-			}
-		} else {
-			d.logger.Error("failed making request", "error", err.Error())
-		}
-		select {
-		case <-time.After(d.fingerprintPeriod):
-		case <-ctx.Done():
-			return
-		}
-	}
-}
-
 // Stats streams statistics for the detected devices.
 // Messages should be emitted to the returned channel on the specified interval.
 func (d *Plugin) Stats(ctx context.Context, interval time.Duration) (<-chan *device.StatsResponse, error) {
@@ -149,74 +194,13 @@ func (d *Plugin) Stats(ctx context.Context, interval time.Duration) (<-chan *dev
 	// organizing a plugin is to pass that into a long-running goroutine and
 	// return the channel immediately.
 	outCh := make(chan *device.StatsResponse)
-	go d.doStats(ctx, outCh, interval)
+	go func() {
+		defer close(outCh)
+		d.transport.RunStats(ctx, interval, outCh)
+	}()
 	return outCh, nil
 }
 
-func (d *Plugin) doStats(ctx context.Context, ch chan<- *device.StatsResponse, interval time.Duration) {
-	defer close(ch)
-	for {
-		var response device.StatsResponse
-		if err := d.jsonRequest(ctx, http.MethodGet, statsEndpoint, nil, &response); err == nil {
-			select {
-			case ch <- &response:
-			case <-ctx.Done():
-				return
-			}
-		} else {
-			d.logger.Error("failed making request", "error", err.Error())
-		}
-		select {
-		case <-time.After(interval):
-		case <-ctx.Done():
-			return
-		}
-	}
-}
-
 func (d *Plugin) Reserve(deviceIDs []string) (*device.ContainerReservation, error) {
-	var unmarshalled device.ContainerReservation
-	err := d.jsonRequest(context.Background(), http.MethodPost, reserveEndpoint, deviceIDs, &unmarshalled)
-	return &unmarshalled, err
-}
-
-func (d *Plugin) jsonRequest(ctx context.Context, method, endpoint string, input interface{}, output interface{}) error {
-	var body io.Reader
-	if input != nil {
-		marshalled, err := json.Marshal(input)
-		if err != nil {
-			return fmt.Errorf("failed to marshal request: %v", err)
-		}
-		body = bytes.NewReader(marshalled)
-	}
-	req, err := http.NewRequestWithContext(ctx, method, d.url(endpoint).String(), body)
-	if err != nil {
-		return fmt.Errorf("failed to build request: %v", err)
-	}
-	if input != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %v", err)
-	}
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("request failed: got status %d", resp.StatusCode)
-	}
-	if output != nil {
-		defer resp.Body.Close()
-		// read body
-		respBody, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response: %v", err)
-		}
-		if err := json.Unmarshal(respBody, &output); err != nil {
-			return fmt.Errorf("failed to unmarshal response: %v", err)
-		}
-	}
-	return nil
-}
-
-func (d *Plugin) url(endpoint string) *url.URL {
-	return d.address.JoinPath(endpoint)
+	return d.transport.Reserve(deviceIDs)
 }
@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// HTTPError is returned by jsonRequest when the backend answers with an error
+// status: at once for a non-retryable status, and for a retryable one after
+// the last retry. Its StatusCode lets callers distinguish "the backend
+// rejected the call" (e.g. reservation denied) from "the backend is
+// unreachable".
+type HTTPError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("request failed: got status %d: %s", e.StatusCode, e.Body)
+}
+
+// retryPolicy configures how jsonRequest retries a failed call: retryable
+// status codes and every error that is not an HTTP status (network, request
+// building, authentication or decoding errors) are retried with jittered
+// exponential backoff up to maxRetries; a non-retryable status is returned
+// immediately.
+type retryPolicy struct {
+	maxRetries int
+	base       time.Duration
+	max        time.Duration
+	retryable  map[int]bool
+}
+
+func newRetryPolicy(config Config) (*retryPolicy, error) {
+	base, err := time.ParseDuration(config.RetryBase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry_base %q: %v", config.RetryBase, err)
+	}
+	max, err := time.ParseDuration(config.RetryMax)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry_max %q: %v", config.RetryMax, err)
+	}
+	retryable := make(map[int]bool, len(config.RetryableStatusCodes))
+	for _, code := range config.RetryableStatusCodes {
+		retryable[code] = true
+	}
+	return &retryPolicy{
+		maxRetries: config.MaxRetries,
+		base:       base,
+		max:        max,
+		retryable:  retryable,
+	}, nil
+}
+
+func (p *retryPolicy) retryableStatus(code int) bool {
+	return p.retryable[code]
+}
+
+// backoff returns a jittered exponential backoff duration for the given
+// attempt (1-indexed): the base doubled once for each attempt after the
+// first (attempt 1 waits up to base, attempt 2 up to 2*base), capped at max,
+// then picked uniformly from [0, d] (full jitter) to avoid synchronized
+// retries from multiple callers.
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	d := p.base
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= p.max {
+			d = p.max
+			break
+		}
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
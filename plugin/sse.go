@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	fingerprintModePoll     = "poll"
+	fingerprintModeLongPoll = "long_poll"
+	fingerprintModeSSE      = "sse"
+
+	fingerprintStreamEndpoint = "/fingerprint/stream"
+	statsStreamEndpoint       = "/stats/stream"
+
+	// sseReconnectBaseline and sseReconnectLimit bound the backoff used to
+	// reconnect a broken SSE stream.
+	sseReconnectBaseline = time.Second
+	sseReconnectLimit    = time.Minute
+
+	// sseMaxLineSize bounds a single "data:" line's length. It needs to
+	// comfortably exceed bufio.Scanner's 64 KiB default, since a
+	// FingerprintResponse/StatsResponse with many devices or groups is
+	// easily larger than that on its own.
+	sseMaxLineSize = 8 * 1024 * 1024
+)
+
+// validateFingerprintMode checks that mode is one of the supported poll /
+// long_poll / sse modes, shared by the fingerprint_mode and stats_mode
+// config options.
+func validateFingerprintMode(mode string) error {
+	switch mode {
+	case fingerprintModePoll, fingerprintModeLongPoll, fingerprintModeSSE:
+		return nil
+	default:
+		return fmt.Errorf("unknown fingerprint/stats mode %q", mode)
+	}
+}
+
+// streamSSE issues a single GET request with an SSE Accept header and
+// invokes onEvent with each "data:" frame's payload, until the stream ends
+// or ctx is canceled. It does not reconnect; callers loop with backoff.
+func (t *httpTransport) streamSSE(ctx context.Context, u *upstream, endpoint string, onEvent func(payload []byte)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.url(endpoint).String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if err := t.auth.Apply(req, nil); err != nil {
+		return fmt.Errorf("failed to authenticate request: %v", err)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("request failed: got status %d", resp.StatusCode)
+	}
+
+	var data bytes.Buffer
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), sseMaxLineSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line terminates an event.
+			if data.Len() > 0 {
+				onEvent(append([]byte(nil), data.Bytes()...))
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("stream read failed: %v", err)
+	}
+	return fmt.Errorf("stream closed by upstream")
+}
@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig configures transport-level TLS. The top-level tls block is used by
+// the gRPC transport and by the HTTP transport's shared client; auth.mtls uses
+// the same fields for the HTTP client and takes precedence there. A client
+// certificate and a custom CA are each optional.
+type TLSConfig struct {
+	CA         string `codec:"ca"`
+	Cert       string `codec:"cert"`
+	Key        string `codec:"key"`
+	ServerName string `codec:"server_name"`
+}
+
+// buildTLSConfig loads a client *tls.Config from PEM-encoded files on disk.
+// It returns nil if cfg is nil, meaning the caller should fall back to
+// plaintext or to Go's default TLS verification, depending on context.
+func buildTLSConfig(cfg *TLSConfig) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{ServerName: cfg.ServerName}
+	if cfg.CA != "" {
+		ca, err := os.ReadFile(cfg.CA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %q: %v", cfg.CA, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to parse CA file %q", cfg.CA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.Cert != "" || cfg.Key != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,47 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/plugins/device"
+)
+
+// Transport abstracts how the plugin obtains fingerprint and stats updates
+// from the proxied backend and how it forwards reservation requests. The
+// default transport talks to REST backends by polling, long-polling or
+// server-sent events; a gRPC transport is also available for backends that
+// implement the RemoteDeviceProvider streaming service.
+type Transport interface {
+	// RunFingerprint feeds fingerprint updates into ch until ctx is
+	// canceled, at which point it returns.
+	RunFingerprint(ctx context.Context, ch chan<- *device.FingerprintResponse)
+
+	// RunStats feeds stats updates into ch until ctx is canceled, at which
+	// point it returns. Polling modes use interval as the period between
+	// rounds and the gRPC transport passes it to the backend; the HTTP sse
+	// stats mode ignores it and emits an update for every event received.
+	RunStats(ctx context.Context, interval time.Duration, ch chan<- *device.StatsResponse)
+
+	// Reserve requests a reservation for the given device IDs.
+	Reserve(deviceIDs []string) (*device.ContainerReservation, error)
+}
+
+// newTransport builds the Transport described by config.
+func newTransport(config Config, logger log.Logger) (Transport, error) {
+	switch config.Transport {
+	case "", transportHTTP:
+		return newHTTPTransport(config, logger)
+	case transportGRPC:
+		return newGRPCTransport(config, logger)
+	default:
+		return nil, fmt.Errorf("unknown transport %q", config.Transport)
+	}
+}
+
+const (
+	transportHTTP = "http"
+	transportGRPC = "grpc"
+)
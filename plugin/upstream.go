@@ -0,0 +1,176 @@
+package plugin
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/plugins/device"
+)
+
+const (
+	// upstreamBackoffBaseline and upstreamBackoffLimit mirror the doubling
+	// backoff Nomad's own device instance manager uses for stats collection
+	// (statsBackoffBaseline/statsBackoffLimit), so a flapping upstream is
+	// polled less and less often rather than spamming it.
+	upstreamBackoffBaseline = 5 * time.Second
+	upstreamBackoffLimit    = 30 * time.Minute
+)
+
+// UpstreamConfig describes a single proxied backend. Multiple upstreams can
+// be configured; their fingerprint and stats responses are merged into a
+// single response emitted to Nomad.
+type UpstreamConfig struct {
+	Name    string `codec:"name"`
+	Address string `codec:"address"`
+	Timeout string `codec:"timeout"`
+}
+
+// upstream tracks connection details and health state for one configured
+// backend.
+type upstream struct {
+	name    string
+	address *url.URL
+	timeout time.Duration
+
+	mu            sync.Mutex
+	backoff       *backoff
+	cooldownUntil time.Time
+	lastDevices   []*device.Device
+	lastGroups    []*device.DeviceGroupStats
+	lastIndex     string
+	breakers      map[string]*circuitBreaker
+}
+
+func newUpstream(cfg UpstreamConfig) (*upstream, error) {
+	address, err := url.Parse(cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream address %q: %v", cfg.Address, err)
+	}
+	timeout := DefaultTimeout
+	if cfg.Timeout != "" {
+		timeout, err = time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upstream timeout %q: %v", cfg.Timeout, err)
+		}
+	}
+	name := cfg.Name
+	if name == "" {
+		name = address.Host
+	}
+	return &upstream{
+		name:     name,
+		address:  address,
+		timeout:  timeout,
+		backoff:  newBackoff(upstreamBackoffBaseline, upstreamBackoffLimit),
+		breakers: make(map[string]*circuitBreaker),
+	}, nil
+}
+
+// breakerFor returns the circuit breaker for the given endpoint on this
+// upstream, creating it on first use.
+func (u *upstream) breakerFor(endpoint string) *circuitBreaker {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	b, ok := u.breakers[endpoint]
+	if !ok {
+		b = &circuitBreaker{}
+		u.breakers[endpoint] = b
+	}
+	return b
+}
+
+func (u *upstream) url(endpoint string) *url.URL {
+	return u.address.JoinPath(endpoint)
+}
+
+// available reports whether the upstream is out of its failure cooldown.
+func (u *upstream) available() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return time.Now().After(u.cooldownUntil)
+}
+
+func (u *upstream) recordSuccess() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.backoff.Reset()
+	u.cooldownUntil = time.Time{}
+}
+
+// recordFailure opens the cooldown window for the next backoff duration and
+// returns it, for logging.
+func (u *upstream) recordFailure() time.Duration {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	wait := u.backoff.Duration()
+	u.cooldownUntil = time.Now().Add(wait)
+	return wait
+}
+
+func (u *upstream) setLastDevices(devices []*device.Device) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.lastDevices = devices
+}
+
+// devices returns a snapshot of the upstream's last known devices.
+func (u *upstream) devices() []*device.Device {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.lastDevices
+}
+
+func (u *upstream) setLastStatsGroups(groups []*device.DeviceGroupStats) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.lastGroups = groups
+}
+
+// statsGroups returns a snapshot of the upstream's last known stats groups.
+func (u *upstream) statsGroups() []*device.DeviceGroupStats {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.lastGroups
+}
+
+// longPollQuery builds the "wait"/"index" query string for a Consul/Nomad
+// style blocking query: wait is how long the backend may hold the request
+// open, and index is the backend's own cursor from the last response we
+// saw, echoed back so it can tell whether anything has changed since.
+func (u *upstream) longPollQuery(wait time.Duration) string {
+	u.mu.Lock()
+	idx := u.lastIndex
+	u.mu.Unlock()
+	return fmt.Sprintf("wait=%s&index=%s", wait, idx)
+}
+
+// setLastIndex records the backend-issued index from the latest response,
+// so the next request's index= reflects what the backend told us rather
+// than anything the client derived itself. A blank index (backend didn't
+// send one) leaves the cursor unchanged.
+func (u *upstream) setLastIndex(index string) {
+	if index == "" {
+		return
+	}
+	u.mu.Lock()
+	u.lastIndex = index
+	u.mu.Unlock()
+}
+
+// unhealthyDevices returns the upstream's last known devices, all marked
+// unhealthy with the given reason, so a flapping backend doesn't make Nomad
+// simply forget about devices it previously advertised.
+func (u *upstream) unhealthyDevices(reason string) []*device.Device {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	out := make([]*device.Device, len(u.lastDevices))
+	for i, d := range u.lastDevices {
+		copyDev := *d
+		copyDev.Healthy = false
+		copyDev.HealthDescription = reason
+		out[i] = &copyDev
+	}
+	return out
+}